@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/copier"
+)
+
+var (
+	copyPlatform     string
+	copyAllPlatforms bool
+	copyJobs         int
+)
+
+var copyCmd = &cobra.Command{
+	Use:   "copy SRC DST",
+	Short: "Copy an image (or a whole multi-arch index) from SRC to DST",
+	Long: `copy streams SRC to DST, mounting blobs directly at the destination
+registry instead of round-tripping them through the client whenever SRC
+and DST share a registry host. This is the fast path for the common
+"mirror an image from Docker Hub into localhost:5000" workflow.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing source %q: %w", args[0], err)
+		}
+		dst, err := parseRef(args[1])
+		if err != nil {
+			return fmt.Errorf("parsing destination %q: %w", args[1], err)
+		}
+
+		var platform *v1.Platform
+		if copyPlatform != "" {
+			p, err := v1.ParsePlatform(copyPlatform)
+			if err != nil {
+				return fmt.Errorf("parsing --platform %q: %w", copyPlatform, err)
+			}
+			platform = p
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		err = copier.Copy(src.String(), dst.String(), copier.Options{
+			Platform:     platform,
+			AllPlatforms: copyAllPlatforms,
+			Jobs:         copyJobs,
+			CraneOptions: opts,
+		})
+		if err != nil {
+			return fmt.Errorf("copying %s to %s: %w", src, dst, err)
+		}
+
+		fmt.Printf("Copied %s to %s\n", src, dst)
+		return nil
+	},
+}
+
+func init() {
+	copyCmd.Flags().StringVar(&copyPlatform, "platform", "", "copy only this platform (e.g. linux/amd64) from a multi-arch index")
+	copyCmd.Flags().BoolVar(&copyAllPlatforms, "all-platforms", false, "copy every platform of a multi-arch index")
+	copyCmd.Flags().IntVar(&copyJobs, "jobs", 4, "number of blob/child-manifest transfers to run concurrently")
+	rootCmd.AddCommand(copyCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete REF",
+	Short: "Resolve REF's digest and delete its manifest",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refStr := args[0]
+
+		ref, err := parseRef(refStr)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", refStr, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		digest, err := crane.Digest(ref.String(), opts...)
+		if err != nil {
+			return fmt.Errorf("resolving digest for %s: %w", ref, err)
+		}
+
+		delRef := fmt.Sprintf("%s@%s", ref.Context().Name(), digest)
+		if err := crane.Delete(delRef, opts...); err != nil {
+			return fmt.Errorf("deleting %s: %w", delRef, err)
+		}
+
+		fmt.Printf("Deleted %s\n", delRef)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest REF",
+	Short: "Resolve REF's manifest digest, without downloading the manifest body",
+	Long: `digest replaces the manual HEAD /v2/<repo>/manifests/<tag> request the
+old registry_crane_curl.go example made by hand, using remote.Head instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", args[0], err)
+		}
+
+		opts, cancel, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		desc, err := remote.Head(ref, opts...)
+		if err != nil {
+			return fmt.Errorf("resolving digest for %s: %w", ref, err)
+		}
+		if desc.Digest.String() == "" {
+			return fmt.Errorf("no digest found in descriptor for %s", ref)
+		}
+
+		fmt.Println(desc.Digest.String())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/gc"
+)
+
+var (
+	gcDryRun         bool
+	gcDeleteUntagged bool
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Prune stale manifest-list children over the registry's HTTP API",
+	Long: `gc walks --registry's catalog, resolves every tag's manifest and, with
+--delete-untagged, prunes manifest-list children that no longer have a
+tag of their own. It talks to the registry over the Distribution HTTP
+API, so unlike the old "docker exec <container> registry garbage-collect"
+example it works against any spec-compliant registry, local or remote.
+
+The Distribution API has no "list all manifests" endpoint, so gc can only
+see what a surviving tag points at: it reclaims multi-arch index children
+left behind when the index that referenced them moves on, but it cannot
+find or delete a single-platform manifest that was simply re-tagged over
+(push, then push the same tag again at a new digest). Reclaiming that case
+still requires the registry's own storage-level garbage-collect.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reg, err := name.NewRegistry(globalOpts.registry, registryOptions()...)
+		if err != nil {
+			return fmt.Errorf("parsing registry %q: %w", globalOpts.registry, err)
+		}
+
+		craneOpts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		remoteOpts, cancel2, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel2()
+
+		kc, err := authOptions().Keychain()
+		if err != nil {
+			return err
+		}
+
+		report, err := gc.Run(cmd.Context(), reg, gc.Options{
+			Keychain:       kc,
+			RemoteOptions:  remoteOpts,
+			CraneOptions:   craneOpts,
+			DryRun:         gcDryRun,
+			DeleteUntagged: gcDeleteUntagged,
+		})
+		if err != nil {
+			return fmt.Errorf("gc %s: %w", reg, err)
+		}
+
+		fmt.Printf("Scanned %d repositories, %d manifests\n", len(report.Repos), report.ManifestsSeen)
+		if len(report.ManifestsPruned) == 0 {
+			fmt.Println("Nothing to delete.")
+			return nil
+		}
+		for _, ref := range report.ManifestsPruned {
+			fmt.Println("Deleted:", ref)
+		}
+		return nil
+	},
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "print what would be deleted without deleting it")
+	gcCmd.Flags().BoolVar(&gcDeleteUntagged, "delete-untagged", false, "also delete manifest-list children that no longer have a tag")
+	rootCmd.AddCommand(gcCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/spf13/cobra"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load DIR REF",
+	Short: "Read a local OCI image layout directory and push it to a registry",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, refStr := args[0], args[1]
+
+		idx, err := layout.ImageIndexFromPath(dir)
+		if err != nil {
+			return fmt.Errorf("reading OCI layout %s: %w", dir, err)
+		}
+		manifest, err := idx.IndexManifest()
+		if err != nil {
+			return fmt.Errorf("reading index manifest for %s: %w", dir, err)
+		}
+		if len(manifest.Manifests) != 1 {
+			return fmt.Errorf("%s contains %d images; load expects exactly one", dir, len(manifest.Manifests))
+		}
+		img, err := idx.Image(manifest.Manifests[0].Digest)
+		if err != nil {
+			return fmt.Errorf("reading image from %s: %w", dir, err)
+		}
+
+		ref, err := parseRef(refStr)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", refStr, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := crane.Push(img, ref.String(), opts...); err != nil {
+			return fmt.Errorf("pushing %s to %s: %w", dir, ref, err)
+		}
+
+		fmt.Printf("Pushed %s to %s\n", dir, ref)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loadCmd)
+}
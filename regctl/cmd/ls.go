@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+)
+
+var lsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"catalog"},
+	Short:   "List the repositories in --registry's catalog",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reg, err := name.NewRegistry(globalOpts.registry, registryOptions()...)
+		if err != nil {
+			return fmt.Errorf("parsing registry %q: %w", globalOpts.registry, err)
+		}
+
+		opts, cancel, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		repos, err := remote.Catalog(cmd.Context(), reg, opts...)
+		if err != nil {
+			return fmt.Errorf("listing catalog for %s: %w", reg, err)
+		}
+
+		if len(repos) == 0 {
+			fmt.Println("(no repositories)")
+			return nil
+		}
+		for _, r := range repos {
+			fmt.Println(r)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lsCmd)
+}
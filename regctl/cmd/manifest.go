@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest REF",
+	Short: "Print the raw manifest JSON for a reference",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", args[0], err)
+		}
+
+		opts, cancel, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		desc, err := remote.Get(ref, opts...)
+		if err != nil {
+			return fmt.Errorf("fetching manifest for %s: %w", ref, err)
+		}
+
+		fmt.Println(string(desc.Manifest))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+}
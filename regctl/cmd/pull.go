@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+)
+
+var pullOut string
+
+var pullCmd = &cobra.Command{
+	Use:   "pull REF",
+	Short: "Pull an image and save it as a docker-compatible tarball",
+	Long: `pull fetches REF and writes it to a local tarball, the same round-trip
+the old pull_and_save.go example performed against a single hardcoded
+registry/repo/tag.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refStr := args[0]
+
+		ref, err := parseRef(refStr)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", refStr, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		img, err := crane.Pull(ref.String(), opts...)
+		if err != nil {
+			return fmt.Errorf("pulling %s: %w", ref, err)
+		}
+
+		out := pullOut
+		if out == "" {
+			out, err = defaultTarPath(ref.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := crane.Save(img, ref.String(), out); err != nil {
+			return fmt.Errorf("saving %s to %s: %w", ref, out, err)
+		}
+
+		fmt.Printf("Saved %s to %s\n", ref, out)
+		return nil
+	},
+}
+
+// defaultTarPath keeps pull_and_save.go's "downloaded-images" folder when
+// --out isn't given, naming the file after the whole reference (registry
+// included) with "/", ":" and "@" replaced by "_".
+func defaultTarPath(refStr string) (string, error) {
+	const folder = "downloaded-images"
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", folder, err)
+	}
+	name := strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(refStr)
+	return filepath.Join(folder, name+".tar"), nil
+}
+
+func init() {
+	pullCmd.Flags().StringVar(&pullOut, "out", "", "output tar filename (default: downloaded-images/<ref>.tar)")
+	rootCmd.AddCommand(pullCmd)
+}
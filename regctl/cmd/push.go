@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push TAR REF",
+	Short: "Push a local docker-compatible tarball to a registry",
+	Long: `push is the inverse of pull: it loads a tarball produced by "regctl
+pull" (or "docker save") and pushes it to REF, replacing the hardcoded
+crane.Push(img, "localhost:5000/mybusybox:latest", crane.Insecure) call
+in the old main.go.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tar, refStr := args[0], args[1]
+
+		img, err := crane.Load(tar)
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", tar, err)
+		}
+
+		ref, err := parseRef(refStr)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", refStr, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := crane.Push(img, ref.String(), opts...); err != nil {
+			return fmt.Errorf("pushing %s to %s: %w", tar, ref, err)
+		}
+
+		fmt.Printf("Pushed %s to %s\n", tar, ref)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/retain"
+)
+
+var (
+	retainPolicyPath string
+	retainDryRun     bool
+	retainExclude    []string
+)
+
+var retainCmd = &cobra.Command{
+	Use:   "retain",
+	Short: "Delete tags that fail every rule in a retention policy file",
+	Long: `retain reads a YAML list of per-repository policies, each with an
+optional keep_last/keep_days/keep_regex rule, and deletes any tag that
+matches none of them. --exclude-tag is a global safety net applied on
+top of every policy (defaults to "latest").`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if retainPolicyPath == "" {
+			return fmt.Errorf("--policy is required")
+		}
+
+		policies, err := retain.LoadPolicies(retainPolicyPath)
+		if err != nil {
+			return err
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		remoteOpts, cancel2, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel2()
+
+		decisions, err := retain.Run(policies, retain.Options{
+			Registry:      globalOpts.registry,
+			NameOptions:   nameOptions(),
+			DryRun:        retainDryRun,
+			ExcludeTags:   retainExclude,
+			CraneOptions:  opts,
+			RemoteOptions: remoteOpts,
+		})
+		if err != nil {
+			return err
+		}
+
+		report, err := json.MarshalIndent(decisions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling report: %w", err)
+		}
+		fmt.Println(string(report))
+		return nil
+	},
+}
+
+func init() {
+	retainCmd.Flags().StringVar(&retainPolicyPath, "policy", "", "path to a YAML retention policy file")
+	retainCmd.Flags().BoolVar(&retainDryRun, "dry-run", false, "print what would be deleted without deleting it")
+	retainCmd.Flags().StringSliceVar(&retainExclude, "exclude-tag", []string{"latest"}, "tags to always keep, regardless of policy")
+	rootCmd.AddCommand(retainCmd)
+}
@@ -0,0 +1,139 @@
+// Package cmd implements the regctl CLI: a single cobra binary that
+// replaces the old scatter of one-off pull/push/delete/gc `package main`
+// files with authenticated, multi-registry subcommands.
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+
+	"regctl/internal/auth"
+	refutil "regctl/internal/ref"
+)
+
+// globalOpts holds flags shared by every subcommand.
+var globalOpts struct {
+	registry                     string
+	username                     string
+	password                     string
+	forceNonSSL                  bool
+	timeout                      time.Duration
+	googleApplicationCredentials string
+}
+
+// rootCmd is the entry point cobra.Command; subcommands register
+// themselves on it via init() in their own files.
+var rootCmd = &cobra.Command{
+	Use:   "regctl",
+	Short: "regctl is a small, authenticated client for Docker/OCI registries",
+	Long: `regctl talks to Docker Hub, GCR, ECR, and plain-HTTP local registries
+through one binary, replacing the hardcoded single-registry example
+scripts this repo used to carry.`,
+	SilenceUsage: true,
+}
+
+// Execute runs the regctl command tree; main() just reports the error.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	registry := os.Getenv("REG_REGISTRY")
+	if registry == "" {
+		registry = "localhost:5000"
+	}
+
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&globalOpts.registry, "registry", registry, "registry host[:port] (env REG_REGISTRY)")
+	flags.StringVar(&globalOpts.username, "username", "", "registry username")
+	flags.StringVar(&globalOpts.password, "password", "", "registry password")
+	flags.BoolVar(&globalOpts.forceNonSSL, "force-non-ssl", false, "allow plain HTTP registries")
+	flags.DurationVar(&globalOpts.timeout, "timeout", 30*time.Second, "timeout for registry requests")
+	flags.StringVar(&globalOpts.googleApplicationCredentials, "google-application-credentials", "", "path to a Google service-account JSON key, used as a bearer/password authenticator")
+}
+
+// authOptions builds auth.Options from the currently parsed global flags.
+func authOptions() auth.Options {
+	return auth.Options{
+		Username:                     globalOpts.username,
+		Password:                     globalOpts.password,
+		GoogleApplicationCredentials: globalOpts.googleApplicationCredentials,
+	}
+}
+
+// craneOptions builds the crane.Option slice shared by every subcommand
+// that talks to a registry: keychain resolution, --force-non-ssl and the
+// --timeout-bounded context. The returned cancel func must be called (e.g.
+// via defer) once the caller is done with the options.
+func craneOptions() (opts []crane.Option, cancel context.CancelFunc, err error) {
+	kc, err := authOptions().Keychain()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), globalOpts.timeout)
+	opts = []crane.Option{
+		crane.WithAuthFromKeychain(kc),
+		crane.WithContext(ctx),
+	}
+	if globalOpts.forceNonSSL {
+		opts = append(opts, crane.Insecure)
+	}
+	return opts, cancel, nil
+}
+
+// parseRef parses refStr, qualifying it against --registry when it has no
+// registry component of its own, and honoring --force-non-ssl.
+func parseRef(refStr string) (name.Reference, error) {
+	return name.ParseReference(qualify(refStr), nameOptions()...)
+}
+
+// nameOptions returns the name.Option set derived from --force-non-ssl,
+// shared by parseRef and registryOptions. go-containerregistry always pings
+// /v2/ as part of its transport handshake (transport.NewWithContext), with
+// no supported way to skip it, so there is deliberately no flag for that
+// here; see --force-non-ssl for the plain-HTTP case.
+func nameOptions() []name.Option {
+	var opts []name.Option
+	if globalOpts.forceNonSSL {
+		opts = append(opts, name.Insecure)
+	}
+	return opts
+}
+
+// registryOptions is nameOptions, named for call sites that parse a bare
+// name.Registry (e.g. "ls") rather than a full reference.
+func registryOptions() []name.Option {
+	return nameOptions()
+}
+
+// remoteOptions builds the remote.Option slice shared by subcommands that
+// use pkg/v1/remote directly instead of crane: keychain resolution and the
+// --timeout-bounded context. The returned cancel func must be called once
+// the caller is done with the options.
+func remoteOptions() (opts []remote.Option, cancel context.CancelFunc, err error) {
+	kc, err := authOptions().Keychain()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), globalOpts.timeout)
+	opts = []remote.Option{
+		remote.WithAuthFromKeychain(kc),
+		remote.WithContext(ctx),
+	}
+	return opts, cancel, nil
+}
+
+// qualify prefixes ref with --registry when it doesn't already name a
+// host; see internal/ref.Qualify, shared with the retain policy engine,
+// for the exact rule.
+func qualify(ref string) string {
+	return refutil.Qualify(ref, globalOpts.registry)
+}
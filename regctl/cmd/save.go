@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/spf13/cobra"
+)
+
+var saveCmd = &cobra.Command{
+	Use:   "save REF DIR",
+	Short: "Pull an image and write it to a local OCI image layout directory",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refStr, dir := args[0], args[1]
+
+		ref, err := parseRef(refStr)
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", refStr, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		img, err := crane.Pull(ref.String(), opts...)
+		if err != nil {
+			return fmt.Errorf("pulling %s: %w", ref, err)
+		}
+
+		if err := crane.SaveOCI(img, dir); err != nil {
+			return fmt.Errorf("writing OCI layout to %s: %w", dir, err)
+		}
+
+		fmt.Printf("Saved %s as an OCI layout in %s\n", ref, dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(saveCmd)
+}
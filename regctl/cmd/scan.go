@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/scan"
+)
+
+var (
+	scanClairURL  string
+	scanFailOn    string
+	scanCachePath string
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan REF",
+	Short: "Scan REF's installed packages for known CVEs via a Clair v4 endpoint",
+	Long: `scan pulls REF, extracts its installed package list (from dpkg, apk or
+rpm databases) for reporting, submits its layers to --clair-url for
+indexing, and prints the resulting CVEs grouped by severity, in the
+spirit of genuinetools/reg's "vulns" command. Manifests that Clair has
+already indexed are cached (keyed by manifest digest, since that's what
+Clair's index/vulnerability reports are keyed by) so re-scanning the same
+image doesn't resubmit it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if scanClairURL == "" {
+			return fmt.Errorf("--clair-url is required")
+		}
+		failOn := scan.ParseSeverity(scanFailOn)
+
+		ref, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", args[0], err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		img, err := pullImage(ref, opts)
+		if err != nil {
+			return err
+		}
+
+		pkgs, err := scan.ExtractPackages(img)
+		if err != nil {
+			return fmt.Errorf("extracting packages from %s: %w", ref, err)
+		}
+		fmt.Printf("Found %d installed packages in %s\n", len(pkgs), ref)
+
+		cachePath := scanCachePath
+		if cachePath == "" {
+			cachePath = defaultScanCachePath()
+		}
+		cache, err := scan.LoadLayerCache(cachePath)
+		if err != nil {
+			return fmt.Errorf("loading layer cache %s: %w", cachePath, err)
+		}
+
+		kc, err := authOptions().Keychain()
+		if err != nil {
+			return err
+		}
+
+		layers, manifestHash, err := scanLayers(cmd.Context(), ref, img, kc)
+		if err != nil {
+			return err
+		}
+		var newLayers int
+		for _, l := range layers {
+			if !cache.Indexed(l.Digest) {
+				newLayers++
+			}
+		}
+		fmt.Printf("Submitting manifest %s to Clair (%d of %d layers not previously seen)\n", manifestHash, newLayers, len(layers))
+
+		client := &scan.Client{BaseURL: scanClairURL}
+		if cache.ManifestIndexed(manifestHash) {
+			fmt.Println("This manifest was already indexed by Clair; skipping re-submission")
+		} else if err := client.Index(cmd.Context(), manifestHash, layers); err != nil {
+			return fmt.Errorf("indexing %s: %w", ref, err)
+		}
+
+		vulns, err := client.WaitForReport(cmd.Context(), manifestHash)
+		if err != nil {
+			return fmt.Errorf("waiting for vulnerability report for %s: %w", ref, err)
+		}
+		for _, l := range layers {
+			cache.MarkIndexed(l.Digest)
+		}
+		cache.MarkManifestIndexed(manifestHash)
+		if err := cache.Save(); err != nil {
+			return fmt.Errorf("saving layer cache %s: %w", cachePath, err)
+		}
+
+		worst := printVulnerabilities(vulns)
+		if failOn != scan.SeverityUnknown && worst >= failOn {
+			return fmt.Errorf("found vulnerabilities at or above %s severity", scanFailOn)
+		}
+		return nil
+	},
+}
+
+// pullImage fetches ref, wrapping crane's error with a bit more context
+// since scan needs an actual v1.Image to walk layers.
+func pullImage(ref name.Reference, opts []crane.Option) (v1.Image, error) {
+	img, err := crane.Pull(ref.String(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("pulling %s: %w", ref, err)
+	}
+	return img, nil
+}
+
+// scanLayers returns the digest+URL+auth-header triples Clair needs to
+// fetch img's layers itself, plus img's manifest digest to key the
+// index/vulnerability reports by.
+func scanLayers(ctx context.Context, ref name.Reference, img v1.Image, kc authn.Keychain) ([]scan.Layer, string, error) {
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, "", fmt.Errorf("reading manifest digest: %w", err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, "", fmt.Errorf("reading layers: %w", err)
+	}
+
+	repo := ref.Context()
+	headers, err := blobAuthHeaders(ctx, repo, kc)
+	if err != nil {
+		return nil, "", fmt.Errorf("authenticating to %s: %w", repo, err)
+	}
+
+	var out []scan.Layer
+	for _, l := range layers {
+		d, err := l.Digest()
+		if err != nil {
+			return nil, "", fmt.Errorf("reading layer digest: %w", err)
+		}
+		out = append(out, scan.Layer{
+			Digest:  d.String(),
+			URL:     fmt.Sprintf("%s://%s/v2/%s/blobs/%s", repo.Scheme(), repo.RegistryStr(), repo.RepositoryStr(), d),
+			Headers: headers,
+		})
+	}
+	return out, digest.String(), nil
+}
+
+// blobAuthHeaders resolves the headers Clair needs to fetch repo's blobs
+// itself, since Clair pulls layers directly rather than through regctl's
+// keychain: a bearer token for registries using the Docker/OCI token
+// protocol, or a Basic header otherwise. A nil keychain returns no headers,
+// for unauthenticated local registries.
+func blobAuthHeaders(ctx context.Context, repo name.Repository, kc authn.Keychain) (map[string][]string, error) {
+	if kc == nil {
+		return nil, nil
+	}
+	authr, err := kc.Resolve(repo)
+	if err != nil {
+		return nil, err
+	}
+	if authr == authn.Anonymous {
+		return nil, nil
+	}
+
+	challenge, err := transport.Ping(ctx, repo.Registry, http.DefaultTransport)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(challenge.Scheme, "bearer") {
+		cfg, err := authr.Authorization()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case cfg.Auth != "":
+			return map[string][]string{"Authorization": {"Basic " + cfg.Auth}}, nil
+		case cfg.Username != "" || cfg.Password != "":
+			return map[string][]string{"Authorization": {"Basic " + basicAuth(cfg.Username, cfg.Password)}}, nil
+		}
+		return nil, nil
+	}
+
+	tok, err := transport.Exchange(ctx, repo.Registry, authr, http.DefaultTransport, []string{repo.Scope(transport.PullScope)}, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]string{"Authorization": {"Bearer " + tok.Token}}, nil
+}
+
+// basicAuth builds an HTTP Basic Authorization value's credential part.
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+func defaultScanCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ".regctl-scan-cache.json"
+	}
+	return filepath.Join(dir, "regctl", "scan-cache.json")
+}
+
+func printVulnerabilities(vulns map[string]scan.Vulnerability) scan.Severity {
+	bySeverity := map[string][]scan.Vulnerability{}
+	var worst scan.Severity
+	for _, v := range vulns {
+		bySeverity[v.Severity] = append(bySeverity[v.Severity], v)
+		if sev := scan.ParseSeverity(v.Severity); sev > worst {
+			worst = sev
+		}
+	}
+
+	if len(vulns) == 0 {
+		fmt.Println("No known vulnerabilities found.")
+		return worst
+	}
+
+	order := []string{"Critical", "High", "Medium", "Low", "Negligible", "Unknown"}
+	for _, sev := range order {
+		list := bySeverity[sev]
+		if len(list) == 0 {
+			continue
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		fmt.Printf("\n%s (%d):\n", sev, len(list))
+		for _, v := range list {
+			fmt.Printf("  %s  %s  %s\n", v.Name, v.Package, v.Description)
+		}
+	}
+	return worst
+}
+
+func init() {
+	scanCmd.Flags().StringVar(&scanClairURL, "clair-url", "", "Clair v4 API base URL, e.g. http://clair:6060")
+	scanCmd.Flags().StringVar(&scanFailOn, "fail-on", "", "exit non-zero if any CVE at or above this severity is found (negligible|low|medium|high|critical)")
+	scanCmd.Flags().StringVar(&scanCachePath, "cache", "", "path to the layer-index cache (default: $XDG_CACHE_HOME/regctl/scan-cache.json)")
+	rootCmd.AddCommand(scanCmd)
+}
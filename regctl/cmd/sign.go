@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/sign"
+)
+
+var signKeyPath string
+
+var signCmd = &cobra.Command{
+	Use:   "sign REF",
+	Short: "Sign REF's manifest digest and push a cosign-compatible .sig image",
+	Long: `sign reads an ECDSA private key from --key (password via
+$COSIGN_PASSWORD), signs REF's manifest digest, and pushes the
+signature next to REF as "<repo>:sha256-<digest>.sig".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if signKeyPath == "" {
+			return fmt.Errorf("--key is required")
+		}
+
+		ref, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", args[0], err)
+		}
+
+		key, err := os.ReadFile(signKeyPath)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", signKeyPath, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		sigRef, err := sign.Sign(ref, key, []byte(os.Getenv("COSIGN_PASSWORD")), opts...)
+		if err != nil {
+			return fmt.Errorf("signing %s: %w", ref, err)
+		}
+
+		fmt.Printf("Pushed signature %s for %s\n", sigRef, ref)
+		return nil
+	},
+}
+
+func init() {
+	signCmd.Flags().StringVar(&signKeyPath, "key", "cosign.key", "path to a PEM-encoded ECDSA private key")
+	rootCmd.AddCommand(signCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags REPO",
+	Short: "List the tags for a repository",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repo, err := name.NewRepository(qualify(args[0]), nameOptions()...)
+		if err != nil {
+			return fmt.Errorf("parsing repository %q: %w", args[0], err)
+		}
+
+		opts, cancel, err := remoteOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		tags, err := remote.List(repo, opts...)
+		if err != nil {
+			return fmt.Errorf("listing tags for %s: %w", repo, err)
+		}
+
+		if len(tags) == 0 {
+			fmt.Println("(no tags)")
+			return nil
+		}
+		for _, t := range tags {
+			fmt.Println(t)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
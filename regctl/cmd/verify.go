@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"regctl/pkg/sign"
+)
+
+var verifyKeyPath string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify REF",
+	Short: "Verify REF's manifest digest against its cosign-compatible .sig image",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyKeyPath == "" {
+			return fmt.Errorf("--key is required")
+		}
+
+		ref, err := parseRef(args[0])
+		if err != nil {
+			return fmt.Errorf("parsing reference %q: %w", args[0], err)
+		}
+
+		pub, err := os.ReadFile(verifyKeyPath)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", verifyKeyPath, err)
+		}
+
+		opts, cancel, err := craneOptions()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if err := sign.Verify(ref, pub, opts...); err != nil {
+			return fmt.Errorf("verifying %s: %w", ref, err)
+		}
+
+		fmt.Printf("Signature OK for %s\n", ref)
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyKeyPath, "key", "cosign.pub", "path to a PEM-encoded ECDSA public key")
+	rootCmd.AddCommand(verifyCmd)
+}
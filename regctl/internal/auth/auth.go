@@ -0,0 +1,74 @@
+// Package auth resolves registry credentials for regctl's subcommands.
+//
+// Resolution order mirrors crane/docker: explicit --username/--password
+// flags win, then a Google service-account JSON key (matching gcr-clean's
+// auth flow) is used as a password authenticator, and finally
+// authn.DefaultKeychain is consulted so credentials already stored by
+// `docker login` (including for Docker Hub, GCR and ECR credential
+// helpers) keep working.
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// Options carries the global auth-related flags from the root command.
+type Options struct {
+	Username                     string
+	Password                     string
+	GoogleApplicationCredentials string
+}
+
+// Keychain resolves an authn.Keychain based on opts, falling back to
+// authn.DefaultKeychain when no explicit credentials are configured. The
+// returned keychain resolves the same authenticator for every resource it
+// is asked about, whichever registry that resource is on (e.g. both the
+// source and destination of a cross-registry copy).
+func (o Options) Keychain() (authn.Keychain, error) {
+	if o.Username != "" || o.Password != "" {
+		return staticKeychain{&authn.Basic{Username: o.Username, Password: o.Password}}, nil
+	}
+	if o.GoogleApplicationCredentials != "" {
+		authr, err := googleKeyAuthenticator(o.GoogleApplicationCredentials)
+		if err != nil {
+			return nil, fmt.Errorf("loading google application credentials: %w", err)
+		}
+		return staticKeychain{authr}, nil
+	}
+	return authn.DefaultKeychain, nil
+}
+
+// staticKeychain always resolves to the same authenticator, regardless of
+// the resource it is asked to authenticate.
+type staticKeychain struct {
+	authr authn.Authenticator
+}
+
+func (k staticKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.authr, nil
+}
+
+// googleKeyAuthenticator reads a Google service-account JSON key from path
+// and returns it as a password authenticator using "_json_key" as the
+// username, the convention GCR's docker-credential-helper and gcr-clean
+// both rely on.
+func googleKeyAuthenticator(path string) (authn.Authenticator, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var key struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, fmt.Errorf("%s is not a valid service-account JSON key: %w", path, err)
+	}
+	return &authn.Basic{
+		Username: "_json_key",
+		Password: string(raw),
+	}, nil
+}
@@ -0,0 +1,29 @@
+// Package ref qualifies bare image references and repo paths against a
+// configured registry, shared by the root command's flag handling and the
+// retain policy engine so the two don't drift.
+package ref
+
+import "strings"
+
+// Qualify prefixes ref with registry unless ref already names its own host
+// or registry is unset. A reference already names a host when it has a
+// "/" and the segment before the first one looks like a host (contains a
+// "." or ":", or is exactly "localhost"); a reference with no "/" at all
+// is always a bare repo name, never a host, even when a trailing ":tag" or
+// "@digest" contains a colon of its own (e.g. "redis:6",
+// "ubuntu@sha256:...") — so the colon check below only runs on the
+// portion before the first slash, not on the whole string.
+func Qualify(ref, registry string) string {
+	if registry == "" {
+		return ref
+	}
+	slash := strings.IndexByte(ref, '/')
+	if slash < 0 {
+		return registry + "/" + ref
+	}
+	first := ref[:slash]
+	if first == "localhost" || strings.ContainsAny(first, ".:") {
+		return ref
+	}
+	return registry + "/" + ref
+}
@@ -0,0 +1,30 @@
+package ref
+
+import "testing"
+
+func TestQualify(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		registry string
+		want     string
+	}{
+		{"bare repo gets the configured registry prefixed", "golang", "registry.example.com", "registry.example.com/golang"},
+		{"bare repo with no registry configured is left alone", "golang", "", "golang"},
+		{"already-qualified host is left alone", "docker.io/library/golang", "registry.example.com", "docker.io/library/golang"},
+		{"two-component repo path with no host gets qualified", "myorg/golang", "registry.example.com", "registry.example.com/myorg/golang"},
+		{"host:port is left alone", "localhost:5000/golang", "registry.example.com", "localhost:5000/golang"},
+		{"bare localhost host is left alone", "localhost/golang", "registry.example.com", "localhost/golang"},
+		{"tagged bare repo still gets qualified", "redis:6", "registry.example.com", "registry.example.com/redis:6"},
+		{"tagged bare repo with latest still gets qualified", "ubuntu:latest", "registry.example.com", "registry.example.com/ubuntu:latest"},
+		{"digest-pinned bare repo still gets qualified", "ubuntu@sha256:abcd", "registry.example.com", "registry.example.com/ubuntu@sha256:abcd"},
+		{"tagged repo path with no host still gets qualified", "myorg/golang:1.22", "registry.example.com", "registry.example.com/myorg/golang:1.22"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Qualify(tt.ref, tt.registry); got != tt.want {
+				t.Errorf("Qualify(%q, %q) = %q, want %q", tt.ref, tt.registry, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Command regctl is a small registry client CLI, in the spirit of
+// genuinetools/reg and crane, that consolidates the ad-hoc
+// pull/push/delete/gc scripts this repo used to ship as separate
+// `package main` files into one authenticated, multi-registry tool.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"regctl/cmd"
+)
+
+func main() {
+	if err := cmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
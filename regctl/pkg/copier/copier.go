@@ -0,0 +1,200 @@
+// Package copier implements a "crane copy"-style helper for mirroring an
+// image, or a whole multi-arch index, from one reference to another. It
+// replaces the old main.go example, which round-tripped a single image
+// through crane.Pull followed by crane.Push one platform at a time.
+//
+// Blob reuse comes for free from pkg/v1/remote.Write: when the image
+// being pushed was obtained via crane.Pull/remote.Image from the same
+// registry host as the destination, remote.Write mounts each blob with
+// POST /v2/<dst-repo>/blobs/uploads/?mount=<digest>&from=<src-repo>
+// instead of re-uploading it. When source and destination are different
+// hosts, the same call transparently falls back to a streamed upload.
+package copier
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// Options configures a Copy.
+type Options struct {
+	// Platform restricts a multi-arch copy to a single platform (e.g.
+	// "linux/amd64"). Ignored for single-platform sources. Mutually
+	// exclusive with AllPlatforms.
+	Platform *v1.Platform
+	// AllPlatforms copies every child manifest of a multi-arch index,
+	// instead of just the one matching Platform (or the host's platform
+	// when Platform is nil).
+	AllPlatforms bool
+	// Jobs bounds how many child manifests are copied concurrently, and
+	// how many blobs each image push transfers at once. Values <= 1 copy
+	// child manifests sequentially; values < 1 leave the per-image blob
+	// concurrency at crane's default.
+	Jobs int
+	// CraneOptions authenticates and configures every underlying
+	// pull/push (WithAuthFromKeychain, Insecure, WithContext, ...).
+	CraneOptions []crane.Option
+}
+
+// indexManifest is the subset of an index/manifest-list JSON body Copy
+// needs to decide whether src is multi-arch and, if so, what its children
+// are.
+type indexManifest struct {
+	Manifests []v1.Descriptor `json:"manifests"`
+}
+
+// Copy copies src to dst. If src is a multi-arch index, Copy copies each
+// child manifest selected by opts.Platform/opts.AllPlatforms and rebuilds
+// an index at dst pointing at the copies (or, with a single Platform
+// selected, pushes that one child directly as dst). Otherwise it copies
+// the single image directly.
+func Copy(src, dst string, opts Options) error {
+	nameOpts := copyNameOptions(opts)
+
+	srcRef, err := name.ParseReference(src, nameOpts...)
+	if err != nil {
+		return fmt.Errorf("parsing source %q: %w", src, err)
+	}
+	dstRef, err := name.ParseReference(dst, nameOpts...)
+	if err != nil {
+		return fmt.Errorf("parsing destination %q: %w", dst, err)
+	}
+
+	raw, err := crane.Manifest(srcRef.String(), opts.CraneOptions...)
+	if err != nil {
+		return fmt.Errorf("fetching manifest for %s: %w", srcRef, err)
+	}
+
+	var idx indexManifest
+	if err := json.Unmarshal(raw, &idx); err != nil {
+		return fmt.Errorf("decoding manifest for %s: %w", srcRef, err)
+	}
+	if len(idx.Manifests) == 0 {
+		return copyImage(srcRef, dstRef, opts)
+	}
+	return copyIndex(srcRef, dstRef, idx, opts)
+}
+
+// copyNameOptions derives the name.Option set Copy parses srcRef/dstRef
+// with from opts.CraneOptions, so flags like --force-non-ssl (crane.Insecure)
+// reach the raw remote.Head/remote.Put calls copyIndex makes directly, not
+// just the crane.Pull/Push/Manifest calls that re-derive name.Options from
+// crane.Option themselves.
+func copyNameOptions(opts Options) []name.Option {
+	return append([]name.Option{name.WeakValidation}, crane.GetOptions(opts.CraneOptions...).Name...)
+}
+
+// copyImage copies a single-platform image directly.
+func copyImage(srcRef, dstRef name.Reference, opts Options) error {
+	img, err := crane.Pull(srcRef.String(), opts.CraneOptions...)
+	if err != nil {
+		return fmt.Errorf("pulling %s: %w", srcRef, err)
+	}
+	pushOpts := opts.CraneOptions
+	if opts.Jobs > 0 {
+		pushOpts = append(append([]crane.Option{}, opts.CraneOptions...), crane.WithJobs(opts.Jobs))
+	}
+	if err := crane.Push(img, dstRef.String(), pushOpts...); err != nil {
+		return fmt.Errorf("pushing %s to %s: %w", srcRef, dstRef, err)
+	}
+	return nil
+}
+
+// copyIndex copies the children of a multi-arch index selected by opts,
+// running up to opts.Jobs copies concurrently.
+func copyIndex(srcRef, dstRef name.Reference, idx indexManifest, opts Options) error {
+	children := selectChildren(idx.Manifests, opts)
+	if len(children) == 0 {
+		return fmt.Errorf("no child manifest of %s matches the requested platform", srcRef)
+	}
+
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	sem := make(chan struct{}, jobs)
+
+	if len(children) == 1 {
+		// A single platform was selected: dst should be that image
+		// directly, not an index of one, so there's no per-digest copy to
+		// fan out first.
+		childSrc := srcRef.Context().Digest(children[0].Digest.String())
+		return copyImage(childSrc, dstRef, opts)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(children))
+	for i, child := range children {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, child v1.Descriptor) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			childSrc := srcRef.Context().Digest(child.Digest.String())
+			childDst := dstRef.Context().Digest(child.Digest.String())
+			errs[i] = copyImage(childSrc, childDst, opts)
+		}(i, child)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// All platforms: push a fresh copy of the index itself now that every
+	// child manifest it points at lives at dst too.
+	rawIdx, err := crane.Manifest(srcRef.String(), opts.CraneOptions...)
+	if err != nil {
+		return fmt.Errorf("re-fetching index for %s: %w", srcRef, err)
+	}
+	srcDesc, err := remote.Head(srcRef, crane.GetOptions(opts.CraneOptions...).Remote...)
+	if err != nil {
+		return fmt.Errorf("resolving media type for %s: %w", srcRef, err)
+	}
+	taggable := rawManifest{raw: rawIdx, mediaType: srcDesc.MediaType}
+	if err := remote.Put(dstRef, taggable, crane.GetOptions(opts.CraneOptions...).Remote...); err != nil {
+		return fmt.Errorf("pushing index to %s: %w", dstRef, err)
+	}
+	return nil
+}
+
+// rawManifest adapts an already-marshaled manifest body to remote.Taggable,
+// for pushing index bytes Copy re-fetched rather than built locally.
+type rawManifest struct {
+	raw       []byte
+	mediaType types.MediaType
+}
+
+func (r rawManifest) RawManifest() ([]byte, error)        { return r.raw, nil }
+func (r rawManifest) MediaType() (types.MediaType, error) { return r.mediaType, nil }
+
+// selectChildren filters manifests per opts.Platform/opts.AllPlatforms,
+// defaulting to the host's own platform when neither is set (matching what
+// a plain `docker pull` of the same index would resolve to).
+func selectChildren(manifests []v1.Descriptor, opts Options) []v1.Descriptor {
+	if opts.AllPlatforms {
+		return manifests
+	}
+	platform := opts.Platform
+	if platform == nil {
+		platform = &v1.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+	}
+	var out []v1.Descriptor
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.Equals(*platform) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
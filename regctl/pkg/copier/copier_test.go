@@ -0,0 +1,143 @@
+package copier
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func descriptor(digest string, os, arch string) v1.Descriptor {
+	return v1.Descriptor{
+		Digest:   v1.Hash{Algorithm: "sha256", Hex: digest},
+		Platform: &v1.Platform{OS: os, Architecture: arch},
+	}
+}
+
+func TestSelectChildrenAllPlatforms(t *testing.T) {
+	manifests := []v1.Descriptor{
+		descriptor("a", "linux", "amd64"),
+		descriptor("b", "linux", "arm64"),
+	}
+	got := selectChildren(manifests, Options{AllPlatforms: true})
+	if len(got) != 2 {
+		t.Fatalf("got %d children, want 2", len(got))
+	}
+}
+
+func TestSelectChildrenExplicitPlatform(t *testing.T) {
+	manifests := []v1.Descriptor{
+		descriptor("a", "linux", "amd64"),
+		descriptor("b", "linux", "arm64"),
+	}
+	got := selectChildren(manifests, Options{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}})
+	if len(got) != 1 || got[0].Digest.Hex != "b" {
+		t.Fatalf("got %v, want just the arm64 child", got)
+	}
+}
+
+func TestSelectChildrenDefaultsToHostPlatform(t *testing.T) {
+	manifests := []v1.Descriptor{
+		descriptor("host", runtime.GOOS, runtime.GOARCH),
+		descriptor("other", "plan9", "ppc64"),
+	}
+	got := selectChildren(manifests, Options{})
+	if len(got) != 1 || got[0].Digest.Hex != "host" {
+		t.Fatalf("got %v, want just the host-platform child", got)
+	}
+}
+
+func TestSelectChildrenNoMatch(t *testing.T) {
+	manifests := []v1.Descriptor{
+		descriptor("other", "plan9", "ppc64"),
+	}
+	got := selectChildren(manifests, Options{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}})
+	if len(got) != 0 {
+		t.Fatalf("got %v, want no matches", got)
+	}
+}
+
+// TestCopyNameOptionsHonorsForceNonSSL pins the bug from review: copyIndex's
+// direct remote.Head/remote.Put calls (for the all-platforms index push)
+// used to parse refs with only name.WeakValidation, dropping whatever
+// --force-non-ssl (crane.Insecure) the caller resolved into opts.CraneOptions.
+// A host that isn't localhost/an RFC1918 IP defaults to https, so this only
+// resolves to http when the Insecure option actually made it through.
+func TestCopyNameOptionsHonorsForceNonSSL(t *testing.T) {
+	opts := Options{CraneOptions: []crane.Option{crane.Insecure}}
+	ref, err := name.ParseReference("registry.example.com/repo:tag", copyNameOptions(opts)...)
+	if err != nil {
+		t.Fatalf("ParseReference: %v", err)
+	}
+	if scheme := ref.Context().Registry.Scheme(); scheme != "http" {
+		t.Errorf("Scheme() = %q, want %q (crane.Insecure should have reached name.ParseReference)", scheme, "http")
+	}
+}
+
+func TestCopyNameOptionsDefaultsToSecure(t *testing.T) {
+	ref, err := name.ParseReference("registry.example.com/repo:tag", copyNameOptions(Options{})...)
+	if err != nil {
+		t.Fatalf("ParseReference: %v", err)
+	}
+	if scheme := ref.Context().Registry.Scheme(); scheme != "https" {
+		t.Errorf("Scheme() = %q, want %q", scheme, "https")
+	}
+}
+
+// TestCopyAllPlatformsEndToEnd exercises copyIndex's full all-platforms
+// path (per-child copy, then remote.Head/remote.Put of the rebuilt index)
+// against a real in-memory registry, rather than just selectChildren.
+func TestCopyAllPlatformsEndToEnd(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	host := mustHost(t, srv.URL)
+
+	idx, err := random.Index(256, 1, 2)
+	if err != nil {
+		t.Fatalf("random.Index: %v", err)
+	}
+	src, err := name.ParseReference(host + "/src:latest")
+	if err != nil {
+		t.Fatalf("ParseReference(src): %v", err)
+	}
+	if err := remote.WriteIndex(src, idx); err != nil {
+		t.Fatalf("WriteIndex: %v", err)
+	}
+
+	dst := host + "/dst:latest"
+	if err := Copy(src.String(), dst, Options{AllPlatforms: true, Jobs: 2}); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	dstRef, err := name.ParseReference(dst)
+	if err != nil {
+		t.Fatalf("ParseReference(dst): %v", err)
+	}
+	gotIdx, err := remote.Index(dstRef)
+	if err != nil {
+		t.Fatalf("fetching copied index: %v", err)
+	}
+	manifest, err := gotIdx.IndexManifest()
+	if err != nil {
+		t.Fatalf("IndexManifest: %v", err)
+	}
+	if len(manifest.Manifests) != 2 {
+		t.Fatalf("got %d children at dst, want 2", len(manifest.Manifests))
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+	return u.Host
+}
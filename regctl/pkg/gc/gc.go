@@ -0,0 +1,338 @@
+// Package gc implements registry-side garbage collection over the plain
+// Distribution HTTP API, so it works against any spec-compliant registry
+// (including remote ones) instead of requiring `docker exec` into the
+// registry's container.
+//
+// Unlike the registry binary's storage-level garbage-collect, this package
+// can only see what the API exposes: tags and the manifests/blobs they
+// reference. It cannot discover manifests that were pushed and then
+// retagged over with no surviving tag anywhere, since the Distribution
+// spec has no "list all manifests" endpoint. What it *can* do is prune
+// manifest-list children (per-platform manifests referenced by a multi-arch
+// index) that no longer have a tag of their own once the index that used
+// to point at them is gone or updated.
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// manifestAccept covers every manifest media type a registry might hand
+// back for a tag, including OCI and Docker manifest lists.
+const manifestAccept = "application/vnd.docker.distribution.manifest.v2+json," +
+	"application/vnd.docker.distribution.manifest.list.v2+json," +
+	"application/vnd.oci.image.manifest.v1+json," +
+	"application/vnd.oci.image.index.v1+json"
+
+// Options configures a garbage-collection run.
+type Options struct {
+	// Keychain resolves credentials for the raw catalog/tag/manifest HTTP
+	// calls this package makes itself (everything but the final delete).
+	// A nil Keychain talks unauthenticated, which only works against open
+	// local registries.
+	Keychain authn.Keychain
+	// RemoteOptions authenticates and configures the remote.List calls used
+	// to enumerate tags (WithAuthFromKeychain, WithContext, ...).
+	RemoteOptions []remote.Option
+	// CraneOptions authenticates and configures the crane.Delete calls
+	// used to actually remove manifests (WithAuthFromKeychain, Insecure,
+	// WithContext, ...).
+	CraneOptions []crane.Option
+	// DryRun prints what would be deleted instead of deleting it.
+	DryRun bool
+	// DeleteUntagged mirrors the registry binary's --delete-untagged: also
+	// remove manifest-list children that no longer have a tag once their
+	// parent index has moved on.
+	DeleteUntagged bool
+}
+
+// Report summarizes what a Run found and (unless DryRun) deleted.
+type Report struct {
+	Repos           []string
+	ManifestsSeen   int
+	ManifestsPruned []string
+}
+
+// Run walks reg's catalog and prunes unreferenced manifests as described in
+// the package doc.
+func Run(ctx context.Context, reg name.Registry, opts Options) (*Report, error) {
+	catalogClient, err := authenticatedClient(ctx, reg, transport.CatalogScope, opts.Keychain)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating to %s: %w", reg, err)
+	}
+
+	repos, err := listCatalog(ctx, catalogClient, reg)
+	if err != nil {
+		return nil, fmt.Errorf("listing catalog for %s: %w", reg, err)
+	}
+
+	report := &Report{Repos: repos}
+
+	for _, repoName := range repos {
+		repo, err := name.NewRepository(reg.Name()+"/"+repoName, name.WeakValidation)
+		if err != nil {
+			return report, fmt.Errorf("parsing repository %q: %w", repoName, err)
+		}
+
+		tags, err := remote.List(repo, opts.RemoteOptions...)
+		if err != nil {
+			return report, fmt.Errorf("listing tags for %s: %w", repo, err)
+		}
+
+		client, err := authenticatedClient(ctx, repo, transport.PullScope, opts.Keychain)
+		if err != nil {
+			return report, fmt.Errorf("authenticating to %s: %w", repo, err)
+		}
+
+		// tagged holds every manifest digest directly reachable from a tag;
+		// children holds manifest-list children reachable transitively.
+		tagged := map[string]bool{}
+		children := map[string]bool{}
+
+		for _, tag := range tags {
+			ref := repo.Tag(tag)
+			desc, digest, err := headManifest(ctx, client, ref)
+			if err != nil {
+				return report, fmt.Errorf("resolving digest for %s: %w", ref, err)
+			}
+			tagged[digest] = true
+			report.ManifestsSeen++
+
+			if err := walkManifest(ctx, client, repo, digest, desc.MediaType, children); err != nil {
+				return report, fmt.Errorf("walking manifest %s@%s: %w", repo, digest, err)
+			}
+		}
+
+		if !opts.DeleteUntagged {
+			continue
+		}
+		for digest := range children {
+			if tagged[digest] {
+				continue // still directly tagged, keep it
+			}
+			ref := fmt.Sprintf("%s@%s", repo.Name(), digest)
+			if opts.DryRun {
+				fmt.Printf("[dry-run] would delete %s\n", ref)
+				continue
+			}
+			if err := crane.Delete(ref, opts.CraneOptions...); err != nil {
+				return report, fmt.Errorf("deleting %s: %w", ref, err)
+			}
+			report.ManifestsPruned = append(report.ManifestsPruned, ref)
+		}
+	}
+
+	return report, nil
+}
+
+// manifestDescriptor is the subset of a manifest/config JSON body gc needs.
+type manifestDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Layers []struct {
+		Digest string `json:"digest"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest string `json:"digest"`
+	} `json:"manifests"`
+}
+
+// walkManifest fetches the manifest at digest and records the
+// child-manifest digests it references, recursing into manifest lists.
+// Config and layer blob digests are not recorded.
+func walkManifest(ctx context.Context, client *http.Client, repo name.Repository, digest, mediaType string, children map[string]bool) error {
+	body, err := getManifest(ctx, client, repo, digest)
+	if err != nil {
+		return err
+	}
+
+	var m manifestDescriptor
+	if err := json.Unmarshal(body, &m); err != nil {
+		return fmt.Errorf("decoding manifest %s: %w", digest, err)
+	}
+
+	if isIndex(mediaType) || len(m.Manifests) > 0 {
+		for _, child := range m.Manifests {
+			children[child.Digest] = true
+			if err := walkManifest(ctx, client, repo, child.Digest, "", children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Leaf image manifest: its config and layer blobs are referenced, but
+	// gc only ever deletes manifests (not blobs directly), so we don't
+	// need to record them beyond having walked them successfully.
+	return nil
+}
+
+func isIndex(mediaType string) bool {
+	return mediaType == string(types.OCIImageIndex) || mediaType == string(types.DockerManifestList)
+}
+
+// scopedResource is the subset of name.Registry and name.Repository that
+// authenticatedClient needs: enough to resolve a keychain entry and to build
+// the "repository:foo/bar:pull"-style scope a bearer challenge expects.
+type scopedResource interface {
+	authn.Resource
+	Scope(action string) string
+}
+
+// authenticatedClient resolves opts' Keychain against target and wraps the
+// result in an http.Client, for the raw catalog/manifest requests this
+// package makes itself outside of remote.List and crane.Delete. A nil
+// keychain falls back to http.DefaultClient, which only works against
+// unauthenticated local registries.
+func authenticatedClient(ctx context.Context, target scopedResource, action string, kc authn.Keychain) (*http.Client, error) {
+	if kc == nil {
+		return http.DefaultClient, nil
+	}
+	authr, err := kc.Resolve(target)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := transport.NewWithContext(ctx, bareRegistry(target), authr, http.DefaultTransport, []string{target.Scope(action)})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// bareRegistry extracts the name.Registry transport.NewWithContext needs to
+// ping and authenticate, whether target is itself a registry or a repository
+// within one.
+func bareRegistry(target scopedResource) name.Registry {
+	if repo, ok := target.(name.Repository); ok {
+		return repo.Registry
+	}
+	return target.(name.Registry)
+}
+
+// listCatalog walks GET /v2/_catalog, following the Link: <...>; rel="next"
+// header until the registry stops returning one.
+func listCatalog(ctx context.Context, client *http.Client, reg name.Registry) ([]string, error) {
+	var all []string
+	next := fmt.Sprintf("%s://%s/v2/_catalog?n=100", reg.Scheme(), reg.RegistryStr())
+
+	for next != "" {
+		var page struct {
+			Repositories []string `json:"repositories"`
+		}
+		link, err := getJSON(ctx, client, next, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Repositories...)
+		next = resolveNext(reg, next, link)
+	}
+	return all, nil
+}
+
+var linkNextRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// resolveNext extracts the next page URL from a Link header, resolving it
+// relative to the request it came from.
+func resolveNext(reg name.Registry, requestURL, link string) string {
+	m := linkNextRE.FindStringSubmatch(link)
+	if m == nil {
+		return ""
+	}
+	base, err := url.Parse(requestURL)
+	if err != nil {
+		return ""
+	}
+	rel, err := url.Parse(m[1])
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(rel).String()
+}
+
+func getJSON(ctx context.Context, client *http.Client, u string, out interface{}) (link string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: unexpected status %s", u, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("decoding response from %s: %w", u, err)
+	}
+	return resp.Header.Get("Link"), nil
+}
+
+// manifestHead is what headManifest can learn without downloading the body.
+type manifestHead struct {
+	MediaType string
+}
+
+// headManifest resolves ref's digest and media type via HEAD, matching the
+// Accept headers a puller would send for either Docker or OCI manifests.
+func headManifest(ctx context.Context, client *http.Client, ref name.Reference) (manifestHead, string, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.Context().Scheme(), ref.Context().RegistryStr(), ref.Context().RepositoryStr(), ref.Identifier())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return manifestHead{}, "", err
+	}
+	req.Header.Set("Accept", manifestAccept)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return manifestHead{}, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return manifestHead{}, "", fmt.Errorf("HEAD %s: unexpected status %s", u, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return manifestHead{}, "", fmt.Errorf("HEAD %s: no Docker-Content-Digest header", u)
+	}
+	return manifestHead{MediaType: resp.Header.Get("Content-Type")}, digest, nil
+}
+
+// getManifest fetches the raw manifest body for repo@digest.
+func getManifest(ctx context.Context, client *http.Client, repo name.Repository, digest string) ([]byte, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", repo.Scheme(), repo.RegistryStr(), repo.RepositoryStr(), digest)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: unexpected status %s", u, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,123 @@
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+func TestIsIndex(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		want      bool
+	}{
+		{"application/vnd.oci.image.index.v1+json", true},
+		{"application/vnd.docker.distribution.manifest.list.v2+json", true},
+		{"application/vnd.oci.image.manifest.v1+json", false},
+		{"application/vnd.docker.distribution.manifest.v2+json", false},
+	}
+	for _, tt := range tests {
+		if got := isIndex(tt.mediaType); got != tt.want {
+			t.Errorf("isIndex(%q) = %v, want %v", tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNext(t *testing.T) {
+	reg, err := name.NewRegistry("registry.example.com")
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	requestURL := "https://registry.example.com/v2/_catalog?n=100"
+	link := `</v2/_catalog?last=foo&n=100>; rel="next"`
+	got := resolveNext(reg, requestURL, link)
+	want := "https://registry.example.com/v2/_catalog?last=foo&n=100"
+	if got != want {
+		t.Errorf("resolveNext() = %q, want %q", got, want)
+	}
+
+	if got := resolveNext(reg, requestURL, ""); got != "" {
+		t.Errorf("resolveNext() with no Link header = %q, want empty", got)
+	}
+}
+
+func TestListCatalogFollowsPagination(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/_catalog", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("last") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/_catalog?last=repo-a&n=100>; rel="next"`))
+			json.NewEncoder(w).Encode(map[string][]string{"repositories": {"repo-a"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": {"repo-b"}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+	reg, err := name.NewRegistry(u.Host, name.Insecure)
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	repos, err := listCatalog(context.Background(), srv.Client(), reg)
+	if err != nil {
+		t.Fatalf("listCatalog: %v", err)
+	}
+
+	want := []string{"repo-a", "repo-b"}
+	if len(repos) != len(want) {
+		t.Fatalf("got %v, want %v", repos, want)
+	}
+	for i := range want {
+		if repos[i] != want[i] {
+			t.Errorf("repos[%d] = %q, want %q", i, repos[i], want[i])
+		}
+	}
+}
+
+func TestWalkManifestRecursesIndexChildren(t *testing.T) {
+	const childDigest = "sha256:" + "11111111111111111111111111111111111111111111111111111111111111"
+	const grandchildManifest = `{"mediaType":"application/vnd.oci.image.manifest.v1+json"}`
+	indexManifest := fmt.Sprintf(`{"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[{"digest":%q}]}`, childDigest)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/repo/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		digest := r.URL.Path[len("/v2/repo/manifests/"):]
+		if digest == childDigest {
+			w.Write([]byte(grandchildManifest))
+			return
+		}
+		w.Write([]byte(indexManifest))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parsing test server URL: %v", err)
+	}
+	repo, err := name.NewRepository(u.Host+"/repo", name.Insecure)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+
+	children := map[string]bool{}
+	if err := walkManifest(context.Background(), srv.Client(), repo, "sha256:root", "application/vnd.oci.image.index.v1+json", children); err != nil {
+		t.Fatalf("walkManifest: %v", err)
+	}
+
+	if !children[childDigest] {
+		t.Errorf("children = %v, want it to contain %q", children, childDigest)
+	}
+}
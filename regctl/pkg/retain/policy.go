@@ -0,0 +1,63 @@
+// Package retain implements a tag-retention policy engine, turning the
+// old single-tag delete example into an operable cleanup tool for a
+// registry that has accumulated hundreds of tags.
+package retain
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy describes which tags of one repository are safe to delete. A tag
+// is kept if it matches ANY rule; it's only deleted once it fails all of
+// them.
+type Policy struct {
+	Repo string `yaml:"repo"`
+	// KeepLast keeps the KeepLast most recently created tags. Zero means
+	// this rule doesn't apply.
+	KeepLast int `yaml:"keep_last"`
+	// KeepDays keeps any tag created within the last KeepDays days. Zero
+	// means this rule doesn't apply.
+	KeepDays int `yaml:"keep_days"`
+	// KeepRegex keeps any tag whose name matches this regular expression,
+	// e.g. "^v\\d+\\.\\d+\\.\\d+$" to always keep semver release tags.
+	KeepRegex string `yaml:"keep_regex"`
+
+	compiledRegex *regexp.Regexp
+}
+
+// LoadPolicies reads a YAML list of Policy documents from path.
+func LoadPolicies(path string) ([]Policy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var policies []Policy
+	if err := yaml.Unmarshal(raw, &policies); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for i, p := range policies {
+		if p.Repo == "" {
+			return nil, fmt.Errorf("%s: policy %d is missing \"repo\"", path, i)
+		}
+		if p.KeepRegex != "" {
+			re, err := regexp.Compile(p.KeepRegex)
+			if err != nil {
+				return nil, fmt.Errorf("%s: repo %q has an invalid keep_regex: %w", path, p.Repo, err)
+			}
+			policies[i].compiledRegex = re
+		}
+	}
+	return policies, nil
+}
+
+// matchesRegex reports whether tag matches the policy's KeepRegex, or
+// false if no KeepRegex was set.
+func (p Policy) matchesRegex(tag string) bool {
+	return p.compiledRegex != nil && p.compiledRegex.MatchString(tag)
+}
@@ -0,0 +1,70 @@
+package retain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	const yaml = `
+- repo: example.com/app
+  keep_last: 5
+  keep_regex: "^v\\d+\\.\\d+\\.\\d+$"
+- repo: example.com/other
+  keep_days: 30
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("writing policy file: %v", err)
+	}
+
+	policies, err := LoadPolicies(path)
+	if err != nil {
+		t.Fatalf("LoadPolicies: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("got %d policies, want 2", len(policies))
+	}
+
+	app := policies[0]
+	if app.Repo != "example.com/app" || app.KeepLast != 5 {
+		t.Errorf("policies[0] = %+v", app)
+	}
+	if !app.matchesRegex("v1.2.3") {
+		t.Errorf("matchesRegex(%q) = false, want true", "v1.2.3")
+	}
+	if app.matchesRegex("latest") {
+		t.Errorf("matchesRegex(%q) = true, want false", "latest")
+	}
+
+	other := policies[1]
+	if other.matchesRegex("v1.2.3") {
+		t.Errorf("policies[1] has no keep_regex but matched anyway")
+	}
+}
+
+func TestLoadPoliciesRequiresRepo(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte("- keep_last: 1\n"), 0o644); err != nil {
+		t.Fatalf("writing policy file: %v", err)
+	}
+
+	if _, err := LoadPolicies(path); err == nil {
+		t.Fatal("LoadPolicies with no repo: want error, got nil")
+	}
+}
+
+func TestLoadPoliciesInvalidRegex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte("- repo: example.com/app\n  keep_regex: \"(\"\n"), 0o644); err != nil {
+		t.Fatalf("writing policy file: %v", err)
+	}
+
+	if _, err := LoadPolicies(path); err == nil {
+		t.Fatal("LoadPolicies with invalid keep_regex: want error, got nil")
+	}
+}
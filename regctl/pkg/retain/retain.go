@@ -0,0 +1,143 @@
+package retain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	refutil "regctl/internal/ref"
+)
+
+// Options configures a Run.
+type Options struct {
+	// Registry prefixes any policy.Repo that doesn't already name its own
+	// registry host, mirroring --registry's effect on every other
+	// subcommand's bare repo/reference arguments.
+	Registry string
+	// NameOptions configures how policy.Repo is parsed (name.Insecure for
+	// --force-non-ssl, etc.), the same as every other subcommand's
+	// reference/repository parsing.
+	NameOptions []name.Option
+	// DryRun prints what would be deleted instead of deleting it.
+	DryRun bool
+	// ExcludeTags are always kept regardless of policy, a global safety
+	// net for e.g. "latest".
+	ExcludeTags []string
+	// CraneOptions authenticates and configures the crane.Delete calls
+	// (WithAuthFromKeychain, Insecure, WithContext, ...).
+	CraneOptions []crane.Option
+	// RemoteOptions authenticates and configures the remote.List/remote.Image
+	// calls used to enumerate and inspect tags (WithAuthFromKeychain,
+	// WithContext, ...).
+	RemoteOptions []remote.Option
+}
+
+// Decision records what Run decided about one tag, for the JSON report.
+type Decision struct {
+	Repo      string    `json:"repo"`
+	Tag       string    `json:"tag"`
+	Created   time.Time `json:"created"`
+	Deleted   bool      `json:"deleted"`
+	KeptBy    string    `json:"kept_by,omitempty"`
+	DeleteErr string    `json:"delete_err,omitempty"`
+}
+
+// tagInfo is a tag paired with its image's creation time, sorted newest
+// first for the KeepLast rule.
+type tagInfo struct {
+	name    string
+	created time.Time
+}
+
+// Run applies each policy to its repository's tags and deletes (or, with
+// Options.DryRun, reports) whichever fail every keep rule.
+func Run(policies []Policy, opts Options) ([]Decision, error) {
+	var decisions []Decision
+
+	for _, policy := range policies {
+		repo, err := name.NewRepository(refutil.Qualify(policy.Repo, opts.Registry), opts.NameOptions...)
+		if err != nil {
+			return decisions, fmt.Errorf("parsing repository %q: %w", policy.Repo, err)
+		}
+
+		tags, err := remote.List(repo, opts.RemoteOptions...)
+		if err != nil {
+			return decisions, fmt.Errorf("listing tags for %s: %w", repo, err)
+		}
+
+		infos, err := tagInfos(repo, tags, opts.RemoteOptions)
+		if err != nil {
+			return decisions, err
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].created.After(infos[j].created) })
+
+		for rank, info := range infos {
+			decision := Decision{Repo: policy.Repo, Tag: info.name, Created: info.created}
+
+			if keptBy, kept := keepReason(policy, opts.ExcludeTags, info, rank); kept {
+				decision.KeptBy = keptBy
+				decisions = append(decisions, decision)
+				continue
+			}
+
+			decision.Deleted = true
+			ref := repo.Tag(info.name)
+			if opts.DryRun {
+				decisions = append(decisions, decision)
+				continue
+			}
+			if err := crane.Delete(ref.String(), opts.CraneOptions...); err != nil {
+				decision.Deleted = false
+				decision.DeleteErr = err.Error()
+			}
+			decisions = append(decisions, decision)
+		}
+	}
+
+	return decisions, nil
+}
+
+// keepReason evaluates every keep rule for info and reports the first one
+// that applies, if any.
+func keepReason(policy Policy, excludeTags []string, info tagInfo, rank int) (reason string, kept bool) {
+	for _, ex := range excludeTags {
+		if info.name == ex {
+			return "excluded", true
+		}
+	}
+	if policy.KeepLast > 0 && rank < policy.KeepLast {
+		return "keep_last", true
+	}
+	if policy.KeepDays > 0 && time.Since(info.created) <= time.Duration(policy.KeepDays)*24*time.Hour {
+		return "keep_days", true
+	}
+	if policy.matchesRegex(info.name) {
+		return "keep_regex", true
+	}
+	return "", false
+}
+
+
+// tagInfos resolves each tag's image creation time via partial.ConfigFile,
+// which only needs the manifest and config blob rather than a full pull.
+func tagInfos(repo name.Repository, tags []string, remoteOpts []remote.Option) ([]tagInfo, error) {
+	infos := make([]tagInfo, 0, len(tags))
+	for _, tag := range tags {
+		ref := repo.Tag(tag)
+		img, err := remote.Image(ref, remoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", ref, err)
+		}
+		cfg, err := partial.ConfigFile(img)
+		if err != nil {
+			return nil, fmt.Errorf("reading config for %s: %w", ref, err)
+		}
+		infos = append(infos, tagInfo{name: tag, created: cfg.Created.Time})
+	}
+	return infos, nil
+}
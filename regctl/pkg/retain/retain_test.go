@@ -0,0 +1,45 @@
+package retain
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestKeepReason(t *testing.T) {
+	policy := Policy{
+		Repo:          "example.com/app",
+		KeepLast:      2,
+		KeepDays:      7,
+		compiledRegex: regexp.MustCompile(`^v\d+$`),
+	}
+	exclude := []string{"latest"}
+
+	tests := []struct {
+		name      string
+		info      tagInfo
+		rank      int
+		wantKept  bool
+		wantByKey string
+	}{
+		{"excluded tag always kept", tagInfo{name: "latest", created: oldTime()}, 10, true, "excluded"},
+		{"within keep_last rank", tagInfo{name: "abc123", created: oldTime()}, 1, true, "keep_last"},
+		{"outside keep_last but recent", tagInfo{name: "abc123", created: time.Now()}, 5, true, "keep_days"},
+		{"matches keep_regex", tagInfo{name: "v1", created: oldTime()}, 5, true, "keep_regex"},
+		{"fails every rule", tagInfo{name: "abc123", created: oldTime()}, 5, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keptBy, kept := keepReason(policy, exclude, tt.info, tt.rank)
+			if kept != tt.wantKept || keptBy != tt.wantByKey {
+				t.Errorf("keepReason() = %q, %v; want %q, %v", keptBy, kept, tt.wantByKey, tt.wantKept)
+			}
+		})
+	}
+}
+
+func oldTime() time.Time {
+	return time.Now().Add(-365 * 24 * time.Hour)
+}
+
@@ -0,0 +1,85 @@
+package scan
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// cacheData is LayerCache's on-disk JSON shape.
+type cacheData struct {
+	// Layers records which layer digests have been submitted to Clair
+	// before, purely for the "N not previously indexed" progress message.
+	Layers map[string]bool `json:"layers"`
+	// Manifests records which image manifest digests Clair has already
+	// finished indexing. This is what actually gates re-submission: Clair
+	// indexes (and keys index/vulnerability reports) by manifest digest,
+	// not by layer, so two manifests can share every layer digest yet
+	// still need their own Index() call (e.g. a re-tag that only changed
+	// the config's created timestamp).
+	Manifests map[string]bool `json:"manifests"`
+}
+
+// LayerCache remembers which manifests Clair has already indexed, so
+// re-scanning the same image doesn't re-submit it. A different image that
+// only shares layers with an indexed one is still submitted with all of
+// its layers.
+type LayerCache struct {
+	path string
+	data cacheData
+}
+
+// LoadLayerCache reads the cache at path, treating a missing file as an
+// empty cache.
+func LoadLayerCache(path string) (*LayerCache, error) {
+	c := &LayerCache{path: path, data: cacheData{Layers: map[string]bool{}, Manifests: map[string]bool{}}}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &c.data); err != nil {
+		return nil, err
+	}
+	if c.data.Layers == nil {
+		c.data.Layers = map[string]bool{}
+	}
+	if c.data.Manifests == nil {
+		c.data.Manifests = map[string]bool{}
+	}
+	return c, nil
+}
+
+// Indexed reports whether digest (a layer digest) has been submitted to
+// Clair before. This is informational only; ManifestIndexed is what
+// actually gates re-submission.
+func (c *LayerCache) Indexed(digest string) bool {
+	return c.data.Layers[digest]
+}
+
+// MarkIndexed records digest (a layer digest) as submitted.
+func (c *LayerCache) MarkIndexed(digest string) {
+	c.data.Layers[digest] = true
+}
+
+// ManifestIndexed reports whether manifestHash has already been indexed
+// (and presumably still is, on Clair's side) by a previous scan.
+func (c *LayerCache) ManifestIndexed(manifestHash string) bool {
+	return c.data.Manifests[manifestHash]
+}
+
+// MarkManifestIndexed records manifestHash as indexed.
+func (c *LayerCache) MarkManifestIndexed(manifestHash string) {
+	c.data.Manifests[manifestHash] = true
+}
+
+// Save persists the cache back to its path.
+func (c *LayerCache) Save() error {
+	raw, err := json.Marshal(c.data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, raw, 0o644)
+}
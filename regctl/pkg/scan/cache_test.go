@@ -0,0 +1,60 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLayerCacheManifestIndexedIsKeyedByManifestNotLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := LoadLayerCache(path)
+	if err != nil {
+		t.Fatalf("LoadLayerCache: %v", err)
+	}
+
+	const sharedLayer = "sha256:shared"
+	const manifestA = "sha256:a"
+	const manifestB = "sha256:b"
+
+	cache.MarkIndexed(sharedLayer)
+	cache.MarkManifestIndexed(manifestA)
+
+	if !cache.Indexed(sharedLayer) {
+		t.Error("Indexed(sharedLayer) = false, want true")
+	}
+	if !cache.ManifestIndexed(manifestA) {
+		t.Error("ManifestIndexed(manifestA) = false, want true")
+	}
+	// manifestB shares every layer digest with manifestA (e.g. a re-tag
+	// that only changed the config), but Clair indexes by manifest digest,
+	// so it must not be considered already-indexed just because its layers
+	// are.
+	if cache.ManifestIndexed(manifestB) {
+		t.Error("ManifestIndexed(manifestB) = true, want false: sharing layers with an indexed manifest doesn't make this one indexed")
+	}
+
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := LoadLayerCache(path)
+	if err != nil {
+		t.Fatalf("LoadLayerCache (reload): %v", err)
+	}
+	if !reloaded.ManifestIndexed(manifestA) {
+		t.Error("after reload, ManifestIndexed(manifestA) = false, want true")
+	}
+	if reloaded.ManifestIndexed(manifestB) {
+		t.Error("after reload, ManifestIndexed(manifestB) = true, want false")
+	}
+}
+
+func TestLoadLayerCacheMissingFile(t *testing.T) {
+	cache, err := LoadLayerCache(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("LoadLayerCache: %v", err)
+	}
+	if cache.ManifestIndexed("sha256:anything") {
+		t.Error("a fresh cache should report nothing as indexed")
+	}
+}
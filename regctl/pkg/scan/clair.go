@@ -0,0 +1,217 @@
+// Package scan implements a "regctl scan" subcommand in the spirit of
+// genuinetools/reg's "vulns" command: it extracts the installed package
+// list from a pulled image and asks a Clair v4 instance to report CVEs
+// against it.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Severity mirrors Clair v4's normalized severity levels, ordered from
+// least to most severe so callers can compare with >=.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityNegligible
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[string]Severity{
+	"Unknown":    SeverityUnknown,
+	"Negligible": SeverityNegligible,
+	"Low":        SeverityLow,
+	"Medium":     SeverityMedium,
+	"High":       SeverityHigh,
+	"Critical":   SeverityCritical,
+}
+
+// ParseSeverity maps a Clair severity string to a Severity, ignoring case
+// (so "high" and "High" are the same), defaulting to SeverityUnknown for
+// anything it doesn't recognize.
+func ParseSeverity(s string) Severity {
+	for name, sev := range severityNames {
+		if strings.EqualFold(name, s) {
+			return sev
+		}
+	}
+	return SeverityUnknown
+}
+
+// Vulnerability is the subset of a Clair v4 VulnerabilityReport entry
+// this package needs.
+type Vulnerability struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Severity    string `json:"normalized_severity"`
+	Package     string `json:"package_name"`
+}
+
+// vulnerabilityReport is the relevant shape of Clair v4's
+// GET /matcher/api/v1/vulnerability_report/{hash} response.
+type vulnerabilityReport struct {
+	Vulnerabilities map[string]Vulnerability `json:"vulnerabilities"`
+}
+
+// indexReport is the relevant shape of Clair v4's
+// GET /indexer/api/v1/index_report/{hash} response.
+type indexReport struct {
+	ManifestHash string `json:"manifest_hash"`
+	State        string `json:"state"`
+	Success      bool   `json:"success"`
+	Err          string `json:"err"`
+}
+
+// manifestRequest is the body Clair v4 expects at
+// POST /indexer/api/v1/index_report: a manifest hash plus its layers'
+// URLs and headers.
+type manifestRequest struct {
+	Hash   string             `json:"hash"`
+	Layers []manifestReqLayer `json:"layers"`
+}
+
+type manifestReqLayer struct {
+	Hash    string              `json:"hash"`
+	URI     string              `json:"uri"`
+	Headers map[string][]string `json:"headers,omitempty"`
+}
+
+// Layer is one image layer to report to Clair: its digest, a URL Clair can
+// fetch its (compressed) contents from, and any headers (e.g.
+// Authorization) Clair needs to send to fetch it, since Clair pulls layers
+// itself rather than going through regctl's keychain.
+type Layer struct {
+	Digest  string
+	URL     string
+	Headers map[string][]string
+}
+
+// Client talks to a Clair v4 indexer/matcher deployment.
+type Client struct {
+	// BaseURL is Clair's API root, e.g. "http://clair:6060".
+	BaseURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// PollInterval controls how often WaitForReport re-checks Clair while
+	// it finishes indexing. Defaults to 2s.
+	PollInterval time.Duration
+}
+
+func (c *Client) client() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Index submits manifestHash and its layers to Clair's indexer.
+func (c *Client) Index(ctx context.Context, manifestHash string, layers []Layer) error {
+	req := manifestRequest{Hash: manifestHash}
+	for _, l := range layers {
+		req.Layers = append(req.Layers, manifestReqLayer{Hash: l.Digest, URI: l.URL, Headers: l.Headers})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshaling index request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/indexer/api/v1/index_report", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("posting index report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("clair returned %s indexing %s", resp.Status, manifestHash)
+	}
+	return nil
+}
+
+// WaitForReport polls Clair until manifestHash finishes indexing (or ctx
+// is done) and returns its vulnerability report.
+func (c *Client) WaitForReport(ctx context.Context, manifestHash string) (map[string]Vulnerability, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	for {
+		report, done, err := c.indexReport(ctx, manifestHash)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			if !report.Success {
+				return nil, fmt.Errorf("clair indexing failed: %s", report.Err)
+			}
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return c.vulnerabilityReport(ctx, manifestHash)
+}
+
+func (c *Client) indexReport(ctx context.Context, manifestHash string) (indexReport, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/indexer/api/v1/index_report/"+manifestHash, nil)
+	if err != nil {
+		return indexReport{}, false, err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return indexReport{}, false, fmt.Errorf("fetching index report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return indexReport{}, false, fmt.Errorf("clair returned %s fetching index report for %s", resp.Status, manifestHash)
+	}
+
+	var report indexReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return indexReport{}, false, fmt.Errorf("decoding index report: %w", err)
+	}
+	return report, report.State == "IndexFinished", nil
+}
+
+func (c *Client) vulnerabilityReport(ctx context.Context, manifestHash string) (map[string]Vulnerability, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/matcher/api/v1/vulnerability_report/"+manifestHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching vulnerability report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("clair returned %s fetching vulnerability report for %s", resp.Status, manifestHash)
+	}
+
+	var report vulnerabilityReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decoding vulnerability report: %w", err)
+	}
+	return report.Vulnerabilities, nil
+}
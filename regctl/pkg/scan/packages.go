@@ -0,0 +1,194 @@
+package scan
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Package is one installed package extracted from a layer's package
+// database.
+type Package struct {
+	Name    string
+	Version string
+	// Source names the package database the entry came from, one of
+	// "dpkg", "apk" or "rpm".
+	Source string
+}
+
+// Package database paths this scanner knows how to read, taken from
+// Debian/Ubuntu, Alpine and RHEL/Fedora/CentOS base images respectively.
+const (
+	dpkgStatusPath   = "var/lib/dpkg/status"
+	apkInstalledPath = "lib/apk/db/installed"
+	rpmPackagesPath  = "var/lib/rpm/Packages"
+)
+
+// ExtractPackages walks img's layers looking for a package database and
+// returns the packages it finds along with which database it read. Layers
+// are walked from base to top so a later layer's database (e.g. after a
+// "RUN apt-get upgrade") wins over an earlier one.
+func ExtractPackages(img v1.Image) ([]Package, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("reading layers: %w", err)
+	}
+
+	var pkgs []Package
+	for _, layer := range layers {
+		found, err := extractFromLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			pkgs = found // last (topmost) database found wins
+		}
+	}
+	return pkgs, nil
+}
+
+// extractFromLayer scans one layer's tarball for a known package database
+// path, returning nil if the layer has none.
+func extractFromLayer(layer v1.Layer) ([]Package, error) {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, fmt.Errorf("reading layer: %w", err)
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar entry: %w", err)
+		}
+
+		switch strings.TrimPrefix(hdr.Name, "./") {
+		case dpkgStatusPath:
+			return parseDpkgStatus(tr)
+		case apkInstalledPath:
+			return parseApkInstalled(tr)
+		case rpmPackagesPath:
+			return parseRPMPackages(tr)
+		}
+	}
+}
+
+// parseDpkgStatus parses Debian's RFC822-ish /var/lib/dpkg/status format,
+// where each installed package is a "Package:"/"Version:" stanza
+// separated by blank lines.
+func parseDpkgStatus(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+	var name, version string
+
+	flush := func() {
+		if name != "" && version != "" {
+			pkgs = append(pkgs, Package{Name: name, Version: version, Source: "dpkg"})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "Package: "):
+			name = strings.TrimPrefix(line, "Package: ")
+		case strings.HasPrefix(line, "Version: "):
+			version = strings.TrimPrefix(line, "Version: ")
+		}
+	}
+	flush()
+	return pkgs, scanner.Err()
+}
+
+// parseApkInstalled parses Alpine's /lib/apk/db/installed format, where
+// each package's fields are "P:" (name)/"V:" (version) lines separated by
+// blank lines.
+func parseApkInstalled(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+	var name, version string
+
+	flush := func() {
+		if name != "" && version != "" {
+			pkgs = append(pkgs, Package{Name: name, Version: version, Source: "apk"})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "P:"):
+			name = strings.TrimPrefix(line, "P:")
+		case strings.HasPrefix(line, "V:"):
+			version = strings.TrimPrefix(line, "V:")
+		}
+	}
+	flush()
+	return pkgs, scanner.Err()
+}
+
+// nevraRE matches "name-version-release" tokens embedded in the raw bytes
+// of an rpm Berkeley DB / NDB Packages file.
+var nevraRE = regexp.MustCompile(`[a-zA-Z0-9_.+-]{2,}-[0-9][a-zA-Z0-9_.+~]*-[0-9][a-zA-Z0-9_.+~]*`)
+
+// parseRPMPackages best-effort extracts installed package names from
+// var/lib/rpm/Packages. The file is a Berkeley DB (or, on newer distros,
+// an "ndb"/"sqlite" variant) that this package has no driver for; instead
+// it scans the raw bytes for printable NEVRA-shaped strings, the same
+// heuristic lightweight scanners without a cgo bdb dependency fall back
+// to. It is best-effort and may miss or duplicate entries.
+func parseRPMPackages(r io.Reader) ([]Package, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading rpm database: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var pkgs []Package
+	for _, tok := range nevraRE.FindAll(raw, -1) {
+		s := string(bytes.TrimSpace(tok))
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		name, version, ok := splitNEVRA(s)
+		if !ok {
+			continue
+		}
+		pkgs = append(pkgs, Package{Name: name, Version: version, Source: "rpm"})
+	}
+	return pkgs, nil
+}
+
+// splitNEVRA splits "name-version-release" at its last two hyphens.
+func splitNEVRA(s string) (name, version string, ok bool) {
+	i := strings.LastIndex(s, "-")
+	if i < 0 {
+		return "", "", false
+	}
+	release := s[i+1:]
+	rest := s[:i]
+	j := strings.LastIndex(rest, "-")
+	if j < 0 {
+		return "", "", false
+	}
+	return rest[:j], rest[j+1:] + "-" + release, true
+}
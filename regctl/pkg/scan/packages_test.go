@@ -0,0 +1,90 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDpkgStatus(t *testing.T) {
+	const status = `Package: base-files
+Status: install ok installed
+Version: 12ubuntu4
+
+Package: libc6
+Status: install ok installed
+Version: 2.35-0ubuntu3
+
+`
+	pkgs, err := parseDpkgStatus(strings.NewReader(status))
+	if err != nil {
+		t.Fatalf("parseDpkgStatus: %v", err)
+	}
+	want := []Package{
+		{Name: "base-files", Version: "12ubuntu4", Source: "dpkg"},
+		{Name: "libc6", Version: "2.35-0ubuntu3", Source: "dpkg"},
+	}
+	assertPackagesEqual(t, pkgs, want)
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	const installed = `P:musl
+V:1.2.4-r2
+A:x86_64
+
+P:busybox
+V:1.36.1-r15
+A:x86_64
+
+`
+	pkgs, err := parseApkInstalled(strings.NewReader(installed))
+	if err != nil {
+		t.Fatalf("parseApkInstalled: %v", err)
+	}
+	want := []Package{
+		{Name: "musl", Version: "1.2.4-r2", Source: "apk"},
+		{Name: "busybox", Version: "1.36.1-r15", Source: "apk"},
+	}
+	assertPackagesEqual(t, pkgs, want)
+}
+
+func TestSplitNEVRA(t *testing.T) {
+	tests := []struct {
+		in         string
+		name, vers string
+		ok         bool
+	}{
+		{"bash-5.2.15-1.fc38", "bash", "5.2.15-1.fc38", true},
+		{"glibc-2.37-9.fc38", "glibc", "2.37-9.fc38", true},
+		{"no-hyphens-at-all", "no-hyphens", "at-all", true},
+		{"nohyphen", "", "", false},
+	}
+	for _, tt := range tests {
+		name, vers, ok := splitNEVRA(tt.in)
+		if ok != tt.ok || name != tt.name || vers != tt.vers {
+			t.Errorf("splitNEVRA(%q) = %q, %q, %v; want %q, %q, %v", tt.in, name, vers, ok, tt.name, tt.vers, tt.ok)
+		}
+	}
+}
+
+func TestParseRPMPackagesDedupes(t *testing.T) {
+	raw := []byte("garbage\x00bash-5.2.15-1.fc38\x00more garbage\x00bash-5.2.15-1.fc38\x00glibc-2.37-9.fc38\x00")
+	pkgs, err := parseRPMPackages(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parseRPMPackages: %v", err)
+	}
+	if len(pkgs) != 2 {
+		t.Fatalf("got %d packages, want 2 (dedup of repeated bash entry): %+v", len(pkgs), pkgs)
+	}
+}
+
+func assertPackagesEqual(t *testing.T, got, want []Package) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d packages, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("package %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
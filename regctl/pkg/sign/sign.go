@@ -0,0 +1,302 @@
+// Package sign implements cosign-compatible signing and verification of
+// image manifests, so images moved between a source registry and the
+// local one carry a supply-chain guarantee without shelling out to the
+// cosign binary.
+//
+// A signature is stored the same way cosign stores it: a small OCI image,
+// tagged "sha256-<digest>.sig" in the signed repository, whose single
+// layer is the JSON "simple signing" payload and whose layer annotation
+// "dev.cosignproject.cosign/signature" holds the base64 ECDSA signature
+// over that payload.
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// signatureAnnotation is the well-known cosign annotation key that carries
+// the base64 signature over a signature layer's payload.
+const signatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// simpleSigningMediaType is the media type cosign uses for its signature
+// payload layers.
+const simpleSigningMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+// payload is cosign's "simple signing" format: an identity/digest pair
+// that pins the signature to one exact manifest of one exact reference.
+type payload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// Sign computes ref's manifest digest, signs it with key (an optionally
+// password-protected PEM-encoded ECDSA private key), and pushes the
+// resulting signature image to "<repo>:sha256-<digest>.sig". It returns
+// the signature reference it pushed to.
+func Sign(ref name.Reference, key, password []byte, opts ...crane.Option) (name.Reference, error) {
+	digest, err := crane.Digest(ref.String(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("resolving digest for %s: %w", ref, err)
+	}
+
+	priv, err := parseECPrivateKey(key, password)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+
+	body, err := buildPayload(ref, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("signing payload: %w", err)
+	}
+
+	layer := static.NewLayer(body, simpleSigningMediaType)
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: layer,
+		Annotations: map[string]string{
+			signatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building signature image: %w", err)
+	}
+
+	sigRef := SignatureTag(ref.Context(), digest)
+	if err := crane.Push(img, sigRef.String(), opts...); err != nil {
+		return nil, fmt.Errorf("pushing signature %s: %w", sigRef, err)
+	}
+	return sigRef, nil
+}
+
+// Verify resolves ref's manifest digest, fetches its signature image, and
+// checks every layer's signature annotation against pub (a PEM-encoded
+// ECDSA public key). It returns nil if at least one layer verifies.
+func Verify(ref name.Reference, pub []byte, opts ...crane.Option) error {
+	digest, err := crane.Digest(ref.String(), opts...)
+	if err != nil {
+		return fmt.Errorf("resolving digest for %s: %w", ref, err)
+	}
+
+	pubKey, err := parseECPublicKey(pub)
+	if err != nil {
+		return fmt.Errorf("parsing public key: %w", err)
+	}
+
+	sigRef := SignatureTag(ref.Context(), digest)
+	sigImg, err := crane.Pull(sigRef.String(), opts...)
+	if err != nil {
+		return fmt.Errorf("pulling signature %s: %w", sigRef, err)
+	}
+	manifest, err := sigImg.Manifest()
+	if err != nil {
+		return fmt.Errorf("reading signature manifest %s: %w", sigRef, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return fmt.Errorf("%s has no signature layers", sigRef)
+	}
+
+	for _, desc := range manifest.Layers {
+		sigB64, ok := desc.Annotations[signatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(sigB64)
+		if err != nil {
+			continue
+		}
+		layer, err := sigImg.LayerByDigest(desc.Digest)
+		if err != nil {
+			continue
+		}
+		rc, err := layer.Uncompressed()
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(body)
+		if ecdsa.VerifyASN1(pubKey, sum[:], sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no valid signature for %s found in %s", ref, sigRef)
+}
+
+// SignatureTag returns the "sha256-<digest>.sig" tag cosign uses to store
+// repo's signature for the manifest identified by digest.
+func SignatureTag(repo name.Repository, digest string) name.Tag {
+	sum := digest
+	if idx := indexOfColon(digest); idx >= 0 {
+		sum = digest[idx+1:]
+	}
+	return repo.Tag(fmt.Sprintf("sha256-%s.sig", sum))
+}
+
+func indexOfColon(s string) int {
+	for i, c := range s {
+		if c == ':' {
+			return i
+		}
+	}
+	return -1
+}
+
+func buildPayload(ref name.Reference, digest string) ([]byte, error) {
+	var p payload
+	p.Critical.Identity.DockerReference = ref.Name()
+	p.Critical.Image.DockerManifestDigest = digest
+	p.Critical.Type = "cosign container image signature"
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling signature payload: %w", err)
+	}
+	return body, nil
+}
+
+// cosignEncryptedKeyType is the PEM block type `cosign generate-key-pair`
+// writes for password-protected keys.
+const cosignEncryptedKeyType = "ENCRYPTED COSIGN PRIVATE KEY"
+
+// cosignKeyBytes is the secretbox key size go-securesystemslib/encrypted
+// derives via scrypt, in bytes.
+const cosignKeyBytes = 32
+
+// encryptedKey is the JSON envelope go-securesystemslib/encrypted.Encrypt
+// writes as the PEM body of an "ENCRYPTED COSIGN PRIVATE KEY" block, i.e.
+// what `cosign generate-key-pair` actually produces for cosign.key. The
+// scrypt params are stored alongside the salt rather than assumed, so they
+// round-trip even if cosign changes its defaults.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func parseECPrivateKey(pemBytes, password []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in key")
+	}
+
+	der := block.Bytes
+	if block.Type == cosignEncryptedKeyType {
+		if len(password) == 0 {
+			return nil, fmt.Errorf("key is password-protected, set $COSIGN_PASSWORD")
+		}
+		decrypted, err := decryptCosignKey(der, password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting key with $COSIGN_PASSWORD: %w", err)
+		}
+		der = decrypted
+	}
+
+	// cosign marshals its generated keys as PKCS8, but accept a bare SEC1
+	// EC key too (e.g. `openssl ecparam -genkey`) for keys brought in from
+	// elsewhere.
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key is not an ECDSA private key")
+		}
+		return ecKey, nil
+	}
+	key, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("not an EC private key: %w", err)
+	}
+	return key, nil
+}
+
+// decryptCosignKey reverses go-securesystemslib/encrypted's key encryption:
+// the PEM body is a JSON envelope naming the scrypt KDF params and salt used
+// to derive a secretbox key, plus the nonce and ciphertext it was sealed
+// with.
+func decryptCosignKey(data, password []byte) ([]byte, error) {
+	var env encryptedKey
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("not a go-securesystemslib encrypted key envelope: %w", err)
+	}
+	if env.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported KDF %q", env.KDF.Name)
+	}
+	if env.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported cipher %q", env.Cipher.Name)
+	}
+	if len(env.Cipher.Nonce) != 24 {
+		return nil, fmt.Errorf("invalid secretbox nonce length %d", len(env.Cipher.Nonce))
+	}
+	var nonce [24]byte
+	copy(nonce[:], env.Cipher.Nonce)
+
+	derived, err := scrypt.Key(password, env.KDF.Salt, env.KDF.Params.N, env.KDF.Params.R, env.KDF.Params.P, cosignKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	var key [32]byte
+	copy(key[:], derived)
+
+	out, ok := secretbox.Open(nil, env.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, fmt.Errorf("incorrect password")
+	}
+	return out, nil
+}
+
+func parseECPublicKey(pemBytes []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("not a PKIX public key: %w", err)
+	}
+	ecKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not an ECDSA public key")
+	}
+	return ecKey, nil
+}
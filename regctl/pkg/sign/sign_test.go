@@ -0,0 +1,185 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestBuildPayload(t *testing.T) {
+	ref, err := name.ParseReference("example.com/repo:latest")
+	if err != nil {
+		t.Fatalf("ParseReference: %v", err)
+	}
+
+	body, err := buildPayload(ref, "sha256:abc")
+	if err != nil {
+		t.Fatalf("buildPayload: %v", err)
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("unmarshaling payload: %v", err)
+	}
+	if p.Critical.Identity.DockerReference != ref.Name() {
+		t.Errorf("DockerReference = %q, want %q", p.Critical.Identity.DockerReference, ref.Name())
+	}
+	if p.Critical.Image.DockerManifestDigest != "sha256:abc" {
+		t.Errorf("DockerManifestDigest = %q, want %q", p.Critical.Image.DockerManifestDigest, "sha256:abc")
+	}
+	if p.Critical.Type != "cosign container image signature" {
+		t.Errorf("Type = %q", p.Critical.Type)
+	}
+}
+
+func TestSignatureTag(t *testing.T) {
+	repo, err := name.NewRepository("example.com/repo")
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+
+	tag := SignatureTag(repo, "sha256:deadbeef")
+	want := "example.com/repo:sha256-deadbeef.sig"
+	if tag.Name() != want {
+		t.Errorf("SignatureTag() = %q, want %q", tag.Name(), want)
+	}
+}
+
+func TestParseECPrivateKeyUnencrypted(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	got, err := parseECPrivateKey(pemBytes, nil)
+	if err != nil {
+		t.Fatalf("parseECPrivateKey: %v", err)
+	}
+	if !got.Equal(priv) {
+		t.Errorf("parsed key does not match the original")
+	}
+}
+
+func TestParseECPrivateKeyEncrypted(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+
+	password := []byte("hunter2")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: cosignEncryptedKeyType, Bytes: encryptForTest(t, der, password)})
+
+	got, err := parseECPrivateKey(pemBytes, password)
+	if err != nil {
+		t.Fatalf("parseECPrivateKey: %v", err)
+	}
+	if !got.Equal(priv) {
+		t.Errorf("parsed key does not match the original")
+	}
+
+	if _, err := parseECPrivateKey(pemBytes, []byte("wrong password")); err == nil {
+		t.Error("parseECPrivateKey with wrong password: want error, got nil")
+	}
+	if _, err := parseECPrivateKey(pemBytes, nil); err == nil {
+		t.Error("parseECPrivateKey with no password for an encrypted key: want error, got nil")
+	}
+}
+
+func TestParseECPublicKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	got, err := parseECPublicKey(pemBytes)
+	if err != nil {
+		t.Fatalf("parseECPublicKey: %v", err)
+	}
+	if !got.Equal(&priv.PublicKey) {
+		t.Errorf("parsed public key does not match the original")
+	}
+}
+
+// encryptForTest builds a go-securesystemslib/encrypted JSON envelope (the
+// format `cosign generate-key-pair` actually writes as the PEM body of an
+// "ENCRYPTED COSIGN PRIVATE KEY" block) against hardcoded literal
+// parameters and field names, independent of decryptCosignKey's own
+// encryptedKey struct, so TestParseECPrivateKeyEncrypted pins
+// parseECPrivateKey to the real wire format rather than just checking it
+// against itself. There's no network access or cosign binary in this
+// environment to source a golden `cosign generate-key-pair` fixture
+// instead; if sigstore/cosign ever changes these parameters, a real
+// fixture would catch that where this can't.
+func encryptForTest(t *testing.T, der, password []byte) []byte {
+	t.Helper()
+
+	const (
+		scryptN  = 1 << 15
+		scryptR  = 8
+		scryptP  = 1
+		keyBytes = 32
+		saltSize = 32
+	)
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatalf("generating salt: %v", err)
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		t.Fatalf("generating nonce: %v", err)
+	}
+
+	derived, err := scrypt.Key(password, salt, scryptN, scryptR, scryptP, keyBytes)
+	if err != nil {
+		t.Fatalf("scrypt.Key: %v", err)
+	}
+	var key [32]byte
+	copy(key[:], derived)
+
+	ciphertext := secretbox.Seal(nil, der, &nonce, &key)
+
+	env := map[string]any{
+		"kdf": map[string]any{
+			"name": "scrypt",
+			"params": map[string]any{
+				"N": scryptN,
+				"r": scryptR,
+				"p": scryptP,
+			},
+			"salt": salt,
+		},
+		"cipher": map[string]any{
+			"name":  "nacl/secretbox",
+			"nonce": nonce[:],
+		},
+		"ciphertext": ciphertext,
+	}
+	out, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("marshaling encrypted key envelope: %v", err)
+	}
+	return out
+}